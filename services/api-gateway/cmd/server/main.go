@@ -17,10 +17,33 @@ import (
 	"os/signal"
 	"time"
 
+	"yagami/api-gateway/internal/broker"
 	"yagami/api-gateway/internal/handlers"
+	"yagami/api-gateway/internal/jobs"
+	"yagami/api-gateway/internal/middleware"
 	"yagami/api-gateway/internal/store"
 )
 
+// defaultEventsRetention is how long events are kept before the
+// events-retention job deletes them, unless overridden by EVENTS_RETENTION.
+const defaultEventsRetention = 90 * 24 * time.Hour
+
+// eventsRetention reads the retention horizon from EVENTS_RETENTION (any
+// format time.ParseDuration accepts, e.g. "2160h" for 90 days), falling
+// back to defaultEventsRetention if it's unset or invalid.
+func eventsRetention() time.Duration {
+	v := os.Getenv("EVENTS_RETENTION")
+	if v == "" {
+		return defaultEventsRetention
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		slog.Warn("invalid EVENTS_RETENTION, using default", "value", v, "default", defaultEventsRetention)
+		return defaultEventsRetention
+	}
+	return d
+}
+
 func main() {
 	// ── Structured logging ──────────────────────────────────
 	// LEARNING: slog outputs JSON logs by default when using NewJSONHandler.
@@ -43,15 +66,41 @@ func main() {
 	defer db.Close()
 	slog.Info("database connected")
 
+	// ── Event broker ────────────────────────────────────────
+	// LEARNING: the broker is an in-process pub/sub hub, not a message
+	// queue — it only fans out events to clients connected to this
+	// process, so it's wired into the store the same way the DB pool is.
+	evBroker := broker.New(broker.DefaultMaxItems, broker.DefaultMaxAge)
+	db.SetBroker(evBroker)
+
+	// ── Background jobs ─────────────────────────────────────
+	// LEARNING: bgCtx is cancelled on shutdown (below), which is how the
+	// scheduler's Start goroutine learns to stop ticking — no separate
+	// stop channel needed, context cancellation does the job.
+	bgCtx, cancelBg := context.WithCancel(context.Background())
+	scheduler := jobs.New(nil)
+	scheduler.Register(jobs.NewStatsRollupJob(db))
+	scheduler.Register(jobs.NewRetentionJob(db, eventsRetention()))
+	scheduler.Start(bgCtx, time.Minute)
+
 	// ── HTTP routes ─────────────────────────────────────────
 	// LEARNING: Go 1.22 added method-based routing to the stdlib.
 	// Before 1.22, you needed a third-party router (chi, gorilla/mux).
 	// Now "GET /path" patterns work natively with http.NewServeMux().
-	h := handlers.New(db)
+	//
+	// LEARNING: each route gets its own time budget via middleware.WithTimeout
+	// instead of one global value — a dashboard query can reasonably take
+	// longer than a health check. /api/events/stream is exempt: it's an
+	// intentionally long-lived SSE connection, not a request with a budget.
+	h := handlers.New(db, evBroker, scheduler)
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /health", h.Health)
-	mux.HandleFunc("GET /api/events", h.ListEvents)
-	mux.HandleFunc("GET /api/stats", h.Stats)
+	mux.Handle("GET /health", middleware.WithTimeout(2*time.Second)(http.HandlerFunc(h.Health)))
+	mux.Handle("GET /api/events", middleware.WithTimeout(5*time.Second)(http.HandlerFunc(h.ListEvents)))
+	mux.HandleFunc("GET /api/events/stream", h.StreamEvents)
+	mux.Handle("GET /api/stats", middleware.WithTimeout(10*time.Second)(http.HandlerFunc(h.Stats)))
+	mux.Handle("GET /api/stats/timeseries", middleware.WithTimeout(10*time.Second)(http.HandlerFunc(h.TimeSeries)))
+	mux.HandleFunc("GET /api/jobs", h.Jobs)
+	mux.HandleFunc("POST /api/jobs/{id}/run", h.RunJob)
 
 	// ── Server + graceful shutdown ──────────────────────────
 	addr := ":8080"
@@ -66,6 +115,7 @@ func main() {
 		<-sigCh // block until signal received
 
 		slog.Info("shutting down...")
+		cancelBg()
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		srv.Shutdown(ctx)
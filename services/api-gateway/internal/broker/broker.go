@@ -0,0 +1,167 @@
+// Package broker implements a small in-process publish/subscribe hub for
+// streaming newly-inserted events to HTTP clients (Server-Sent Events).
+//
+// LEARNING (Go):
+//   - This is modeled after the "ring buffer + per-subscriber cursor" design
+//     used by streaming systems like Nomad's event stream: events are kept
+//     in a bounded buffer, and each subscriber remembers how far it has
+//     read (its cursor) instead of the broker tracking per-client state.
+//   - sync.Cond lets goroutines sleep until someone signals "something
+//     changed" instead of polling in a loop. Broadcast() wakes every
+//     goroutine waiting on the condition so they can re-check the buffer.
+//   - There is no channel-per-event fan-out here on purpose: with many
+//     subscribers, broadcasting through a shared buffer is cheaper than
+//     writing to N channels on every publish.
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Event is a published notification. Payload carries the JSON-encoded
+// store.Event so this package doesn't need to import store.
+type Event struct {
+	Seq       uint64          `json:"-"`
+	EventType string          `json:"-"`
+	Payload   json.RawMessage `json:"-"`
+	CreatedAt time.Time       `json:"-"`
+}
+
+// DefaultMaxItems bounds how many events the ring buffer keeps in memory.
+const DefaultMaxItems = 1024
+
+// DefaultMaxAge is how long a buffered event is kept before it is pruned,
+// regardless of how many subscribers have read it.
+const DefaultMaxAge = 10 * time.Minute
+
+// Broker fans out published events to subscribers. The zero value is not
+// usable; construct one with New.
+type Broker struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	buf      []Event
+	nextSeq  uint64
+	maxItems int
+	maxAge   time.Duration
+}
+
+// New creates a Broker with the given retention policy.
+func New(maxItems int, maxAge time.Duration) *Broker {
+	if maxItems <= 0 {
+		maxItems = DefaultMaxItems
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+	b := &Broker{maxItems: maxItems, maxAge: maxAge}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// Publish appends an event to the buffer and wakes every waiting subscriber.
+func (b *Broker) Publish(eventType string, payload json.RawMessage) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextSeq++
+	b.buf = append(b.buf, Event{
+		Seq:       b.nextSeq,
+		EventType: eventType,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	})
+	b.prune()
+	b.cond.Broadcast()
+}
+
+// prune drops events older than maxAge and trims the buffer down to
+// maxItems. Callers must hold b.mu.
+func (b *Broker) prune() {
+	cutoff := time.Now().Add(-b.maxAge)
+	start := 0
+	for start < len(b.buf) && b.buf[start].CreatedAt.Before(cutoff) {
+		start++
+	}
+	if over := len(b.buf) - start - b.maxItems; over > 0 {
+		start += over
+	}
+	if start > 0 {
+		b.buf = append([]Event(nil), b.buf[start:]...)
+	}
+}
+
+// Subscription tracks one subscriber's read position in the buffer.
+type Subscription struct {
+	b         *Broker
+	eventType string
+	cursor    uint64
+}
+
+// Subscribe returns a Subscription that only yields future events (nothing
+// already in the buffer is replayed — callers that need a backlog should
+// fetch it from the store before subscribing). An empty eventType matches
+// every event.
+func (b *Broker) Subscribe(eventType string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return &Subscription{b: b, eventType: eventType, cursor: b.nextSeq}
+}
+
+// Next blocks until the next event matching the subscription's filter is
+// published, ctx is cancelled, or the event the subscriber was waiting on
+// has aged out of the buffer (a slow subscriber is simply skipped ahead
+// rather than kept waiting forever). It reports ok=false once ctx is done.
+func (s *Subscription) Next(ctx context.Context) (ev Event, ok bool) {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+
+	// LEARNING: sync.Cond.Wait() doesn't understand context cancellation,
+	// so we spawn a one-shot goroutine that broadcasts when ctx is done.
+	// The `done` channel stops that goroutine once we return, so it never
+	// leaks past this call.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.b.mu.Lock()
+			s.b.cond.Broadcast()
+			s.b.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	for {
+		if ctx.Err() != nil {
+			return Event{}, false
+		}
+		if oldest := s.oldestSeqLocked(); oldest > s.cursor+1 {
+			// We fell behind far enough that the buffer pruned events we
+			// hadn't read yet — skip ahead instead of replaying gaps.
+			s.cursor = oldest - 1
+		}
+		for _, e := range s.b.buf {
+			if e.Seq <= s.cursor {
+				continue
+			}
+			if s.eventType != "" && e.EventType != s.eventType {
+				continue
+			}
+			s.cursor = e.Seq
+			return e, true
+		}
+		s.b.cond.Wait()
+	}
+}
+
+// oldestSeqLocked returns the sequence number of the oldest buffered event,
+// or 0 if the buffer is empty. Callers must hold b.mu.
+func (s *Subscription) oldestSeqLocked() uint64 {
+	if len(s.b.buf) == 0 {
+		return 0
+	}
+	return s.b.buf[0].Seq
+}
@@ -0,0 +1,96 @@
+// Package broker — tests for the pub/sub hub.
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBroker_PublishAndNext(t *testing.T) {
+	b := New(0, 0)
+	sub := b.Subscribe("")
+
+	b.Publish("watch", json.RawMessage(`{"id":1}`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ev, ok := sub.Next(ctx)
+	if !ok {
+		t.Fatal("Next() = !ok, want an event")
+	}
+	if ev.EventType != "watch" {
+		t.Errorf("EventType = %q, want %q", ev.EventType, "watch")
+	}
+}
+
+func TestBroker_FiltersByType(t *testing.T) {
+	b := New(0, 0)
+	sub := b.Subscribe("like")
+
+	b.Publish("watch", json.RawMessage(`{}`))
+	b.Publish("like", json.RawMessage(`{"id":2}`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ev, ok := sub.Next(ctx)
+	if !ok {
+		t.Fatal("Next() = !ok, want an event")
+	}
+	if ev.EventType != "like" {
+		t.Errorf("EventType = %q, want %q (watch event should have been filtered out)", ev.EventType, "like")
+	}
+}
+
+func TestBroker_NextBlocksUntilPublish(t *testing.T) {
+	b := New(0, 0)
+	sub := b.Subscribe("")
+
+	result := make(chan bool, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_, ok := sub.Next(ctx)
+		result <- ok
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	b.Publish("subscribe", json.RawMessage(`{}`))
+
+	select {
+	case ok := <-result:
+		if !ok {
+			t.Error("Next() = !ok, want an event after publish")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Next() did not return after publish")
+	}
+}
+
+func TestBroker_NextReturnsFalseOnCancel(t *testing.T) {
+	b := New(0, 0)
+	sub := b.Subscribe("")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, ok := sub.Next(ctx); ok {
+		t.Error("Next() = ok, want !ok for an already-cancelled context")
+	}
+}
+
+func TestBroker_PruneByMaxItems(t *testing.T) {
+	b := New(2, time.Hour)
+
+	b.Publish("a", json.RawMessage(`{}`))
+	b.Publish("b", json.RawMessage(`{}`))
+	b.Publish("c", json.RawMessage(`{}`))
+
+	b.mu.Lock()
+	n := len(b.buf)
+	b.mu.Unlock()
+	if n > 2 {
+		t.Errorf("buffer len = %d, want <= 2 after pruning", n)
+	}
+}
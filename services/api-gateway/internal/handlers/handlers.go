@@ -11,9 +11,15 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"yagami/api-gateway/internal/broker"
+	"yagami/api-gateway/internal/jobs"
 	"yagami/api-gateway/internal/store"
 )
 
@@ -24,17 +30,28 @@ import (
 type Storer interface {
 	Ping(ctx context.Context) error
 	ListEvents(ctx context.Context, eventType string, limit int) ([]store.Event, error)
+	ListEventsPage(ctx context.Context, p store.ListEventsParams) (*store.EventPage, error)
 	GetStats(ctx context.Context) (*store.Stats, error)
+	GetTimeSeries(ctx context.Context, eventType, bucket string, since time.Time) ([]store.TimeSeriesPoint, error)
 }
 
+// keepaliveInterval is how often StreamEvents sends a `:keepalive` comment
+// to keep idle proxies from closing the connection.
+const keepaliveInterval = 15 * time.Second
+
 // Handler holds shared dependencies for all HTTP handlers.
 type Handler struct {
-	store Storer
+	store     Storer
+	broker    *broker.Broker
+	scheduler *jobs.Scheduler
 }
 
 // New creates a Handler. In Go, this is the conventional "constructor".
-func New(s Storer) *Handler {
-	return &Handler{store: s}
+// broker may be nil, in which case StreamEvents still replays the backlog
+// but never sees live updates. scheduler may be nil, in which case the
+// jobs endpoints report an empty job list.
+func New(s Storer, b *broker.Broker, sch *jobs.Scheduler) *Handler {
+	return &Handler{store: s, broker: b, scheduler: sch}
 }
 
 // Health checks if the database is reachable.
@@ -51,8 +68,18 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 }
 
 // ListEvents returns recent events from the database.
-// GET /api/events?type=watch&limit=20
+//
+// By default (and for any client that doesn't opt in) this returns the
+// flat JSON array it always has: GET /api/events?type=watch&limit=20.
+// Passing ?v=2 switches to cursor-based pagination and an envelope
+// response — see listEventsPage — so existing clients aren't broken by
+// the OFFSET-less redesign.
 func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("v") == "2" {
+		h.listEventsPage(w, r)
+		return
+	}
+
 	// LEARNING: r.URL.Query().Get("key") reads a query-string parameter.
 	// It returns "" if the parameter is absent (no error, no nil).
 	eventType := r.URL.Query().Get("type")
@@ -66,7 +93,7 @@ func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
 
 	events, err := h.store.ListEvents(r.Context(), eventType, limit)
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		writeStoreError(w, err)
 		return
 	}
 	// LEARNING: In Go, a nil slice marshals to JSON null, not [].
@@ -77,20 +104,267 @@ func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, events)
 }
 
+// listEventsPage implements the v2, cursor-paginated ListEvents response:
+// GET /api/events?v=2&type=watch&limit=20&after=<cursor>&before=<cursor>&since=<RFC3339>&until=<RFC3339>
+func (h *Handler) listEventsPage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	params := store.ListEventsParams{EventType: q.Get("type")}
+
+	if l := q.Get("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 {
+			params.Limit = v
+		}
+	}
+
+	if since := q.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid since: " + err.Error()})
+			return
+		}
+		params.Since = t
+	}
+	if until := q.Get("until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid until: " + err.Error()})
+			return
+		}
+		params.Until = t
+	}
+
+	if after := q.Get("after"); after != "" {
+		c, err := store.DecodeCursor(after)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		params.After = &c
+	}
+	if before := q.Get("before"); before != "" {
+		c, err := store.DecodeCursor(before)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		params.Before = &c
+	}
+
+	page, err := h.store.ListEventsPage(r.Context(), params)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if page.Events == nil {
+		page.Events = []store.Event{}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
 // Stats returns aggregate activity counts.
 // GET /api/stats
 func (h *Handler) Stats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.store.GetStats(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		writeStoreError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, stats)
 }
 
+// defaultTimeSeriesBucket and defaultTimeSeriesRange apply when the caller
+// omits the corresponding query parameter.
+const (
+	defaultTimeSeriesBucket = "hour"
+	defaultTimeSeriesRange  = 24 * time.Hour
+)
+
+// TimeSeries returns bucketed event counts for sparklines.
+// GET /api/stats/timeseries?bucket=hour&range=24h&type=watch
+func (h *Handler) TimeSeries(w http.ResponseWriter, r *http.Request) {
+	bucket := r.URL.Query().Get("bucket")
+	if bucket == "" {
+		bucket = defaultTimeSeriesBucket
+	}
+	if !store.ValidTimeSeriesBucket(bucket) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid bucket %q, want \"hour\" or \"day\"", bucket)})
+		return
+	}
+
+	lookback, err := parseRange(r.URL.Query().Get("range"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	eventType := r.URL.Query().Get("type")
+
+	points, err := h.store.GetTimeSeries(r.Context(), eventType, bucket, time.Now().Add(-lookback))
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if points == nil {
+		points = []store.TimeSeriesPoint{}
+	}
+	writeJSON(w, http.StatusOK, points)
+}
+
+// parseRange parses a lookback window like "24h" (anything time.ParseDuration
+// accepts) or "7d"/"30d" — time.ParseDuration has no day unit, so a trailing
+// "d" is handled separately.
+func parseRange(s string) (time.Duration, error) {
+	if s == "" {
+		return defaultTimeSeriesRange, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid range %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid range %q", s)
+	}
+	return d, nil
+}
+
+// StreamEvents pushes events to the client as they happen, using
+// Server-Sent Events (text/event-stream). It subscribes to the broker
+// first, then flushes a backlog of recent events from the store, so
+// nothing published in the gap between the two is ever silently dropped;
+// any event delivered both ways (because it landed in the backlog query
+// after the subscription started) is deduped against the backlog before
+// it would otherwise be sent twice.
+// GET /api/events/stream?type=watch
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	eventType := r.URL.Query().Get("type")
+
+	// LEARNING: http.Flusher lets a handler push partial writes to the
+	// client immediately instead of waiting for the handler to return.
+	// It's how Go serves long-lived streaming responses over stdlib HTTP.
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming unsupported"})
+		return
+	}
+
+	// Subscribe before querying the backlog: Subscribe captures the
+	// broker's current sequence number as this subscription's cursor, so
+	// anything published after this point is guaranteed to be seen by
+	// Next() below — even though the backlog query that follows may also
+	// pick up some of those same rows.
+	var sub *broker.Subscription
+	if h.broker != nil {
+		sub = h.broker.Subscribe(eventType)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	backlog, err := h.store.ListEvents(r.Context(), eventType, 50)
+	if err != nil {
+		writeSSEComment(w, "error: "+err.Error())
+		flusher.Flush()
+		return
+	}
+	var maxBacklogID int64
+	for i := len(backlog) - 1; i >= 0; i-- {
+		if err := writeSSEEvent(w, backlog[i]); err != nil {
+			return
+		}
+		if backlog[i].ID > maxBacklogID {
+			maxBacklogID = backlog[i].ID
+		}
+	}
+	flusher.Flush()
+
+	if sub == nil {
+		return
+	}
+
+	ctx := r.Context()
+
+	// LEARNING: Subscription.Next blocks, so it needs its own goroutine —
+	// otherwise we couldn't also wake up on the keepalive ticker below.
+	events := make(chan broker.Event)
+	go func() {
+		defer close(events)
+		for {
+			ev, ok := sub.Next(ctx)
+			if !ok {
+				return
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			var decoded store.Event
+			if err := json.Unmarshal(ev.Payload, &decoded); err == nil && decoded.ID <= maxBacklogID {
+				// Already sent as part of the backlog replay above.
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", ev.Payload); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-keepalive.C:
+			writeSSEComment(w, "keepalive")
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSEEvent writes a single store.Event as an SSE `data:` frame.
+func writeSSEEvent(w http.ResponseWriter, e store.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", payload)
+	return err
+}
+
+// writeSSEComment writes an SSE comment line (ignored by clients, but keeps
+// the connection alive through proxies that time out idle connections).
+func writeSSEComment(w http.ResponseWriter, msg string) {
+	fmt.Fprintf(w, ": %s\n\n", msg)
+}
+
 // writeJSON is a small helper that sets headers and encodes the response.
 func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(v)
 }
+
+// writeStoreError reports an error from a Storer call. A context deadline
+// or cancellation (the request ran past its middleware.WithTimeout budget)
+// is reported as 504 Gateway Timeout instead of a generic 500, so clients
+// can tell "the server is overloaded/slow" apart from "the request failed".
+func writeStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		writeJSON(w, http.StatusGatewayTimeout, map[string]string{"error": "request exceeded its time budget"})
+		return
+	}
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
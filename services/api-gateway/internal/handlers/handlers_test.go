@@ -11,11 +11,14 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"yagami/api-gateway/internal/broker"
 	"yagami/api-gateway/internal/store"
 )
 
@@ -24,10 +27,12 @@ import (
 // mockStore implements the same methods as store.Store so we can
 // test handlers without a real database connection.
 type mockStore struct {
-	events   []store.Event
-	stats    *store.Stats
-	pingErr  error
-	queryErr error
+	events     []store.Event
+	stats      *store.Stats
+	timeSeries []store.TimeSeriesPoint
+	eventPage  *store.EventPage
+	pingErr    error
+	queryErr   error
 }
 
 func (m *mockStore) Ping(ctx context.Context) error {
@@ -59,6 +64,16 @@ func (m *mockStore) ListEvents(ctx context.Context, eventType string, limit int)
 	return filtered, nil
 }
 
+func (m *mockStore) ListEventsPage(ctx context.Context, p store.ListEventsParams) (*store.EventPage, error) {
+	if m.queryErr != nil {
+		return nil, m.queryErr
+	}
+	if m.eventPage != nil {
+		return m.eventPage, nil
+	}
+	return &store.EventPage{}, nil
+}
+
 func (m *mockStore) GetStats(ctx context.Context) (*store.Stats, error) {
 	if m.queryErr != nil {
 		return nil, m.queryErr
@@ -66,6 +81,13 @@ func (m *mockStore) GetStats(ctx context.Context) (*store.Stats, error) {
 	return m.stats, nil
 }
 
+func (m *mockStore) GetTimeSeries(ctx context.Context, eventType, bucket string, since time.Time) ([]store.TimeSeriesPoint, error) {
+	if m.queryErr != nil {
+		return nil, m.queryErr
+	}
+	return m.timeSeries, nil
+}
+
 func (m *mockStore) Close() {}
 
 // newTestHandler creates a Handler with a mock store for testing.
@@ -247,6 +269,19 @@ func TestStats_OK(t *testing.T) {
 }
 
 func TestStats_DBError(t *testing.T) {
+	ms := &mockStore{queryErr: errors.New("boom")}
+	h := newTestHandler(ms)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/stats", nil)
+
+	h.Stats(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestStats_DeadlineExceededIsGatewayTimeout(t *testing.T) {
 	ms := &mockStore{queryErr: context.DeadlineExceeded}
 	h := newTestHandler(ms)
 	rec := httptest.NewRecorder()
@@ -254,11 +289,317 @@ func TestStats_DBError(t *testing.T) {
 
 	h.Stats(rec, req)
 
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusGatewayTimeout)
+	}
+}
+
+// ── ListEvents v2 (cursor pagination) Tests ─────────────────
+
+func TestListEvents_V2ReturnsEnvelope(t *testing.T) {
+	ms := &mockStore{
+		eventPage: &store.EventPage{
+			Events:     []store.Event{{ID: 1, EventType: "watch", CreatedAt: time.Now()}},
+			NextCursor: "abc123",
+		},
+	}
+	h := newTestHandler(ms)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/events?v=2", nil)
+
+	h.ListEvents(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var page store.EventPage
+	json.NewDecoder(rec.Body).Decode(&page)
+	if len(page.Events) != 1 {
+		t.Errorf("got %d events, want 1", len(page.Events))
+	}
+	if page.NextCursor != "abc123" {
+		t.Errorf("NextCursor = %q, want %q", page.NextCursor, "abc123")
+	}
+}
+
+func TestListEvents_V1StillReturnsFlatArray(t *testing.T) {
+	ms := &mockStore{events: []store.Event{{ID: 1, EventType: "watch", CreatedAt: time.Now()}}}
+	h := newTestHandler(ms)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/events", nil)
+
+	h.ListEvents(rec, req)
+
+	var events []store.Event
+	if err := json.NewDecoder(rec.Body).Decode(&events); err != nil {
+		t.Fatalf("v1 response isn't a flat array: %v", err)
+	}
+	if len(events) != 1 {
+		t.Errorf("got %d events, want 1", len(events))
+	}
+}
+
+func TestListEvents_V2InvalidCursor(t *testing.T) {
+	h := newTestHandler(&mockStore{})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/events?v=2&after=not-base64!!", nil)
+
+	h.ListEvents(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestListEvents_V2InvalidSince(t *testing.T) {
+	h := newTestHandler(&mockStore{})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/events?v=2&since=not-a-date", nil)
+
+	h.ListEvents(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+// ── TimeSeries Tests ────────────────────────────────────────
+
+func TestTimeSeries_OK(t *testing.T) {
+	ms := &mockStore{
+		timeSeries: []store.TimeSeriesPoint{
+			{BucketStart: time.Now(), Count: 3},
+			{BucketStart: time.Now(), Count: 5},
+		},
+	}
+	h := newTestHandler(ms)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/stats/timeseries?bucket=hour&range=24h&type=watch", nil)
+
+	h.TimeSeries(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var points []store.TimeSeriesPoint
+	json.NewDecoder(rec.Body).Decode(&points)
+	if len(points) != 2 {
+		t.Errorf("got %d points, want 2", len(points))
+	}
+}
+
+func TestTimeSeries_InvalidRange(t *testing.T) {
+	h := newTestHandler(&mockStore{})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/stats/timeseries?range=nonsense", nil)
+
+	h.TimeSeries(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestTimeSeries_InvalidBucket(t *testing.T) {
+	h := newTestHandler(&mockStore{})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/stats/timeseries?bucket=week", nil)
+
+	h.TimeSeries(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestTimeSeries_DBError(t *testing.T) {
+	ms := &mockStore{queryErr: errors.New("boom")}
+	h := newTestHandler(ms)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/stats/timeseries", nil)
+
+	h.TimeSeries(rec, req)
+
 	if rec.Code != http.StatusInternalServerError {
 		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
 	}
 }
 
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "", want: 24 * time.Hour},
+		{in: "24h", want: 24 * time.Hour},
+		{in: "7d", want: 7 * 24 * time.Hour},
+		{in: "30d", want: 30 * 24 * time.Hour},
+		{in: "not-a-range", wantErr: true},
+		{in: "0d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRange(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRange(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseRange(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// ── StreamEvents Tests ──────────────────────────────────────
+
+func TestStreamEvents_FlushesBacklog(t *testing.T) {
+	ms := &mockStore{
+		events: []store.Event{
+			{ID: 1, EventType: "watch", CreatedAt: time.Now()},
+		},
+	}
+	h := &Handler{store: ms}
+	rec := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req := httptest.NewRequest("GET", "/api/events/stream", nil).WithContext(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		h.StreamEvents(rec, req)
+		close(done)
+	}()
+
+	// No broker means nothing to wait on after the backlog; the handler
+	// returns on its own, but cancel anyway in case that ever changes.
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		cancel()
+		<-done
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want %q", ct, "text/event-stream")
+	}
+	if !strings.Contains(rec.Body.String(), `"event_type":"watch"`) {
+		t.Errorf("body = %q, want it to contain the backlog event", rec.Body.String())
+	}
+}
+
+func TestStreamEvents_PublishesLiveEvents(t *testing.T) {
+	b := broker.New(0, 0)
+	h := &Handler{store: &mockStore{}, broker: b}
+	rec := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/api/events/stream?type=like", nil).WithContext(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		h.StreamEvents(rec, req)
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	b.Publish("watch", json.RawMessage(`{"event_type":"watch"}`))
+	b.Publish("like", json.RawMessage(`{"event_type":"like","id":7}`))
+	time.Sleep(20 * time.Millisecond) // let StreamEvents's goroutine drain both
+
+	cancel()
+	<-done // only read rec.Body below, after the handler goroutine has stopped writing to it
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `"id":7`) {
+		t.Fatalf("body = %q, want it to contain the published like event", body)
+	}
+	if strings.Contains(body, `"event_type":"watch"`) {
+		t.Error("body contains the filtered-out watch event, want only like events")
+	}
+}
+
+// sideEffectStore lets a test run a callback from inside ListEvents, so it
+// can publish to the broker at the exact moment StreamEvents queries the
+// backlog — simulating an insert that lands in the gap between subscribing
+// and querying.
+type sideEffectStore struct {
+	*mockStore
+	onListEvents func()
+}
+
+func (s *sideEffectStore) ListEvents(ctx context.Context, eventType string, limit int) ([]store.Event, error) {
+	if s.onListEvents != nil {
+		s.onListEvents()
+	}
+	return s.mockStore.ListEvents(ctx, eventType, limit)
+}
+
+func TestStreamEvents_SubscribesBeforeQueryingBacklog(t *testing.T) {
+	b := broker.New(0, 0)
+	ms := &sideEffectStore{mockStore: &mockStore{}}
+	ms.onListEvents = func() {
+		// Before the fix, StreamEvents subscribed only after this query
+		// returned, so an event published here — too late for the
+		// backlog, too early for the subscription — was dropped forever.
+		b.Publish("watch", json.RawMessage(`{"id":9,"event_type":"watch"}`))
+	}
+	h := &Handler{store: ms, broker: b}
+
+	rec := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/api/events/stream", nil).WithContext(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		h.StreamEvents(rec, req)
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+
+	if body := rec.Body.String(); !strings.Contains(body, `"id":9`) {
+		t.Errorf("body = %q, want the event published during the backlog query to be delivered", body)
+	}
+}
+
+func TestStreamEvents_DedupesBacklogAndLiveOverlap(t *testing.T) {
+	b := broker.New(0, 0)
+	ms := &mockStore{events: []store.Event{{ID: 5, EventType: "watch", CreatedAt: time.Now()}}}
+	h := &Handler{store: ms, broker: b}
+
+	rec := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/api/events/stream", nil).WithContext(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		h.StreamEvents(rec, req)
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	// Same id the backlog already returned, republished live — as if it
+	// landed in the backlog query's result after the subscription began.
+	b.Publish("watch", json.RawMessage(`{"id":5,"event_type":"watch"}`))
+	b.Publish("watch", json.RawMessage(`{"id":6,"event_type":"watch"}`))
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	<-done
+
+	body := rec.Body.String()
+	if n := strings.Count(body, `"id":5`); n != 1 {
+		t.Errorf(`got %d occurrences of "id":5, want exactly 1 (no duplicate delivery)`, n)
+	}
+	if !strings.Contains(body, `"id":6`) {
+		t.Errorf("body = %q, want the genuinely new live event id 6 to be delivered", body)
+	}
+}
+
 // ── JSON Response Tests ────────────────────────────────────
 
 func TestWriteJSON_ContentType(t *testing.T) {
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"yagami/api-gateway/internal/jobs"
+)
+
+// Jobs reports the status of every registered background job.
+// GET /api/jobs
+func (h *Handler) Jobs(w http.ResponseWriter, r *http.Request) {
+	if h.scheduler == nil {
+		writeJSON(w, http.StatusOK, []jobs.Status{})
+		return
+	}
+	writeJSON(w, http.StatusOK, h.scheduler.Statuses())
+}
+
+// RunJob triggers a registered job immediately, outside its schedule.
+// POST /api/jobs/{id}/run
+func (h *Handler) RunJob(w http.ResponseWriter, r *http.Request) {
+	if h.scheduler == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "no jobs are registered"})
+		return
+	}
+	id := r.PathValue("id")
+	if err := h.scheduler.RunNow(r.Context(), id); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "started"})
+}
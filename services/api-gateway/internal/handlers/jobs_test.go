@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"yagami/api-gateway/internal/jobs"
+)
+
+type noopJob struct{ id string }
+
+func (j *noopJob) ID() string                    { return j.id }
+func (j *noopJob) Schedule() jobs.Schedule       { return jobs.Every(time.Hour) }
+func (j *noopJob) Run(ctx context.Context) error { return nil }
+
+func TestJobs_NoScheduler(t *testing.T) {
+	h := &Handler{store: &mockStore{}}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/jobs", nil)
+
+	h.Jobs(rec, req)
+
+	var statuses []jobs.Status
+	json.NewDecoder(rec.Body).Decode(&statuses)
+	if len(statuses) != 0 {
+		t.Errorf("got %d statuses, want 0 with no scheduler configured", len(statuses))
+	}
+}
+
+func TestJobs_ReturnsRegisteredJobs(t *testing.T) {
+	sched := jobs.New(nil)
+	sched.Register(&noopJob{id: "stats-rollup-1h"})
+	h := &Handler{store: &mockStore{}, scheduler: sched}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/jobs", nil)
+
+	h.Jobs(rec, req)
+
+	var statuses []jobs.Status
+	json.NewDecoder(rec.Body).Decode(&statuses)
+	if len(statuses) != 1 || statuses[0].ID != "stats-rollup-1h" {
+		t.Errorf("statuses = %+v, want one entry for stats-rollup-1h", statuses)
+	}
+}
+
+func TestRunJob_UnknownID(t *testing.T) {
+	sched := jobs.New(nil)
+	h := &Handler{store: &mockStore{}, scheduler: sched}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/jobs/nope/run", nil)
+	req.SetPathValue("id", "nope")
+
+	h.RunJob(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestRunJob_Triggers(t *testing.T) {
+	sched := jobs.New(nil)
+	sched.Register(&noopJob{id: "events-retention"})
+	h := &Handler{store: &mockStore{}, scheduler: sched}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/jobs/events-retention/run", nil)
+	req.SetPathValue("id", "events-retention")
+
+	h.RunJob(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+}
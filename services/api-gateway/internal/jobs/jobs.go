@@ -0,0 +1,209 @@
+// Package jobs runs named periodic background jobs inside the api-gateway
+// process — pre-aggregating stats, pruning old rows — without needing a
+// separate cron container alongside the server.
+//
+// LEARNING (Go):
+//   - sync.Mutex.TryLock (added in Go 1.18) lets us say "do this only if
+//     nothing else is already doing it" without blocking — that's how a
+//     job avoids overlapping itself if one run is still going when the
+//     next is due.
+//   - Injecting a Clock instead of calling time.Now() directly is what
+//     lets tests advance through schedule boundaries deterministically,
+//     the same way the contributors-stats-job in the wider system does.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock abstracts time.Now so tests can control it.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the Clock used in production.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// Schedule decides when a Job should next run.
+type Schedule interface {
+	// Next returns the next run time strictly after `after`.
+	Next(after time.Time) time.Time
+}
+
+// Every runs a job on a fixed interval.
+type Every time.Duration
+
+func (e Every) Next(after time.Time) time.Time { return after.Add(time.Duration(e)) }
+
+// Hourly runs a job once per hour, at the given minute — a cron-like
+// "5 * * * *" without pulling in a cron-expression parser.
+type Hourly int
+
+func (h Hourly) Next(after time.Time) time.Time {
+	next := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), int(h), 0, 0, after.Location())
+	if !next.After(after) {
+		next = next.Add(time.Hour)
+	}
+	return next
+}
+
+// Job is a named unit of background work with its own schedule.
+type Job interface {
+	ID() string
+	Schedule() Schedule
+	Run(ctx context.Context) error
+}
+
+// Status reports a job's most recent run, for GET /api/jobs.
+type Status struct {
+	ID           string    `json:"id"`
+	Running      bool      `json:"running"`
+	LastRun      time.Time `json:"last_run,omitempty"`
+	LastSuccess  time.Time `json:"last_success,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	LastDuration int64     `json:"last_duration_ms"`
+	NextRun      time.Time `json:"next_run"`
+}
+
+// scheduledJob pairs a Job with its runtime state. runMu is the singleton
+// lock: it's held for the duration of Run, so a job never overlaps itself.
+// statusMu guards the small Status snapshot so Statuses() never blocks on
+// a slow Run.
+type scheduledJob struct {
+	job      Job
+	runMu    sync.Mutex
+	statusMu sync.Mutex
+	status   Status
+}
+
+// Scheduler runs registered Jobs as their schedules come due.
+type Scheduler struct {
+	clock Clock
+
+	mu   sync.Mutex
+	jobs map[string]*scheduledJob
+}
+
+// New creates a Scheduler. A nil clock uses time.Now.
+func New(clock Clock) *Scheduler {
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &Scheduler{clock: clock, jobs: make(map[string]*scheduledJob)}
+}
+
+// Register adds a job and computes its first run time from the clock's
+// current time. Registering a job with an ID that's already registered
+// replaces it.
+func (s *Scheduler) Register(j Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sj := &scheduledJob{job: j}
+	sj.status = Status{ID: j.ID(), NextRun: j.Schedule().Next(s.clock.Now())}
+	s.jobs[j.ID()] = sj
+}
+
+// Start runs the scheduler loop in a goroutine, checking for due jobs
+// every `resolution`, until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context, resolution time.Duration) {
+	go func() {
+		ticker := time.NewTicker(resolution)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.Tick(ctx, s.clock.Now())
+			}
+		}
+	}()
+}
+
+// Tick runs every job whose schedule is due at `now`. It's exported
+// directly (rather than only reachable through Start's ticker) so tests
+// can advance a fake clock and assert on exactly which jobs ran.
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	due := make([]*scheduledJob, 0)
+	for _, sj := range s.jobs {
+		sj.statusMu.Lock()
+		isDue := !now.Before(sj.status.NextRun)
+		sj.statusMu.Unlock()
+		if isDue {
+			due = append(due, sj)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, sj := range due {
+		s.run(ctx, sj, now)
+	}
+}
+
+// RunNow triggers a job immediately, regardless of its schedule, for
+// POST /api/jobs/{id}/run. It returns an error if the job is unknown or
+// already running.
+func (s *Scheduler) RunNow(ctx context.Context, id string) error {
+	s.mu.Lock()
+	sj, ok := s.jobs[id]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("jobs: unknown job %q", id)
+	}
+	if !s.run(ctx, sj, s.clock.Now()) {
+		return fmt.Errorf("jobs: %q is already running", id)
+	}
+	return nil
+}
+
+// run executes sj's Job if it isn't already running, and reports whether
+// it actually ran.
+func (s *Scheduler) run(ctx context.Context, sj *scheduledJob, now time.Time) bool {
+	if !sj.runMu.TryLock() {
+		return false
+	}
+	defer sj.runMu.Unlock()
+
+	sj.statusMu.Lock()
+	sj.status.Running = true
+	sj.statusMu.Unlock()
+
+	err := sj.job.Run(ctx)
+	duration := s.clock.Now().Sub(now)
+
+	sj.statusMu.Lock()
+	sj.status.Running = false
+	sj.status.LastRun = now
+	sj.status.LastDuration = duration.Milliseconds()
+	sj.status.NextRun = sj.job.Schedule().Next(now)
+	if err != nil {
+		sj.status.LastError = err.Error()
+	} else {
+		sj.status.LastError = ""
+		sj.status.LastSuccess = now
+	}
+	sj.statusMu.Unlock()
+	return true
+}
+
+// Statuses returns every registered job's status, sorted by ID.
+func (s *Scheduler) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]Status, 0, len(s.jobs))
+	for _, sj := range s.jobs {
+		sj.statusMu.Lock()
+		statuses = append(statuses, sj.status)
+		sj.statusMu.Unlock()
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ID < statuses[j].ID })
+	return statuses
+}
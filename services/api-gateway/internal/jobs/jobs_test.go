@@ -0,0 +1,145 @@
+// Package jobs — tests for the scheduler.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock lets tests move time forward in discrete steps instead of
+// sleeping on the wall clock.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// countingJob counts how many times Run was called and can be told to fail.
+type countingJob struct {
+	id    string
+	sched Schedule
+	runs  atomic.Int32
+	err   error
+}
+
+func (j *countingJob) ID() string         { return j.id }
+func (j *countingJob) Schedule() Schedule { return j.sched }
+func (j *countingJob) Run(ctx context.Context) error {
+	j.runs.Add(1)
+	return j.err
+}
+
+func TestScheduler_TickRunsDueJobs(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := New(clock)
+
+	job := &countingJob{id: "every-hour", sched: Every(time.Hour)}
+	s.Register(job)
+
+	// Not due yet: the first run is scheduled an hour after registration.
+	s.Tick(context.Background(), clock.now)
+	if job.runs.Load() != 0 {
+		t.Fatalf("runs = %d, want 0 before the first tick boundary", job.runs.Load())
+	}
+
+	clock.advance(time.Hour)
+	s.Tick(context.Background(), clock.now)
+	if job.runs.Load() != 1 {
+		t.Fatalf("runs = %d, want 1 after the first tick boundary", job.runs.Load())
+	}
+
+	clock.advance(30 * time.Minute)
+	s.Tick(context.Background(), clock.now)
+	if job.runs.Load() != 1 {
+		t.Fatalf("runs = %d, want 1 (still within the hour)", job.runs.Load())
+	}
+
+	clock.advance(30 * time.Minute)
+	s.Tick(context.Background(), clock.now)
+	if job.runs.Load() != 2 {
+		t.Fatalf("runs = %d, want 2 after the second tick boundary", job.runs.Load())
+	}
+}
+
+func TestScheduler_SingletonLockSkipsOverlap(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	s := New(clock)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	job := &blockingJob{id: "slow", sched: Every(time.Minute), started: started, release: release}
+	s.Register(job)
+
+	go s.RunNow(context.Background(), "slow")
+	<-started // first run is in flight and holding the singleton lock
+
+	if err := s.RunNow(context.Background(), "slow"); err == nil {
+		t.Error("RunNow() error = nil, want an error while the job is already running")
+	}
+
+	close(release)
+}
+
+// blockingJob blocks on release so tests can assert overlap is prevented.
+type blockingJob struct {
+	id               string
+	sched            Schedule
+	started, release chan struct{}
+}
+
+func (j *blockingJob) ID() string         { return j.id }
+func (j *blockingJob) Schedule() Schedule { return j.sched }
+func (j *blockingJob) Run(ctx context.Context) error {
+	close(j.started)
+	<-j.release
+	return nil
+}
+
+func TestScheduler_RunNowUnknownJob(t *testing.T) {
+	s := New(&fakeClock{now: time.Now()})
+	if err := s.RunNow(context.Background(), "does-not-exist"); err == nil {
+		t.Error("RunNow() error = nil, want an error for an unregistered job")
+	}
+}
+
+func TestScheduler_StatusesReportErrors(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	s := New(clock)
+	job := &countingJob{id: "flaky", sched: Every(time.Minute), err: errors.New("boom")}
+	s.Register(job)
+
+	if err := s.RunNow(context.Background(), "flaky"); err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+
+	statuses := s.Statuses()
+	if len(statuses) != 1 {
+		t.Fatalf("got %d statuses, want 1", len(statuses))
+	}
+	if statuses[0].LastError != "boom" {
+		t.Errorf("LastError = %q, want %q", statuses[0].LastError, "boom")
+	}
+	if !statuses[0].LastSuccess.IsZero() {
+		t.Error("LastSuccess should stay zero after a failed run")
+	}
+}
+
+func TestHourly_Next(t *testing.T) {
+	h := Hourly(5)
+
+	before := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	got := h.Next(before)
+	want := time.Date(2026, 1, 1, 10, 5, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", before, got, want)
+	}
+
+	after := time.Date(2026, 1, 1, 10, 10, 0, 0, time.UTC)
+	got = h.Next(after)
+	want = time.Date(2026, 1, 1, 11, 5, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", after, got, want)
+	}
+}
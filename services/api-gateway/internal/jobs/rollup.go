@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+// statsRoller is the slice of store.Store that StatsRollupJob needs.
+type statsRoller interface {
+	RollupStatsHourly(ctx context.Context) error
+}
+
+// StatsRollupJob writes pre-aggregated hourly counts into stats_hourly so
+// GetStats can read a handful of rows instead of COUNT-ing the whole
+// events table.
+type StatsRollupJob struct {
+	store statsRoller
+}
+
+// NewStatsRollupJob creates a StatsRollupJob, run 5 minutes past the hour
+// so it rolls up a settled hour rather than racing in-flight inserts.
+func NewStatsRollupJob(s statsRoller) *StatsRollupJob {
+	return &StatsRollupJob{store: s}
+}
+
+func (j *StatsRollupJob) ID() string         { return "stats-rollup-1h" }
+func (j *StatsRollupJob) Schedule() Schedule { return Hourly(5) }
+
+func (j *StatsRollupJob) Run(ctx context.Context) error {
+	return j.store.RollupStatsHourly(ctx)
+}
+
+// eventPruner is the slice of store.Store that RetentionJob needs.
+type eventPruner interface {
+	DeleteEventsOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
+
+// RetentionJob deletes events older than a configurable horizon so the
+// events table doesn't grow unbounded.
+type RetentionJob struct {
+	store   eventPruner
+	horizon time.Duration
+	now     func() time.Time
+}
+
+// NewRetentionJob creates a RetentionJob that deletes events older than
+// horizon, checked once a day.
+func NewRetentionJob(s eventPruner, horizon time.Duration) *RetentionJob {
+	return &RetentionJob{store: s, horizon: horizon, now: time.Now}
+}
+
+func (j *RetentionJob) ID() string         { return "events-retention" }
+func (j *RetentionJob) Schedule() Schedule { return Every(24 * time.Hour) }
+
+func (j *RetentionJob) Run(ctx context.Context) error {
+	_, err := j.store.DeleteEventsOlderThan(ctx, j.now().Add(-j.horizon))
+	return err
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// DeadlineTimer is a re-armable, net.Conn-style deadline: SetDeadline can
+// be called repeatedly, and each call hands out a fresh Done() channel, so
+// a deadline that was replaced before it elapsed can never fire. A waiter
+// still holding the channel from before a re-arm is not woken by the new
+// deadline; it has to fetch Done() again.
+type DeadlineTimer struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	ch    chan struct{}
+}
+
+// NewDeadlineTimer creates a DeadlineTimer with no deadline set; Done()
+// never fires until SetDeadline is called.
+func NewDeadlineTimer() *DeadlineTimer {
+	return &DeadlineTimer{ch: make(chan struct{})}
+}
+
+// Done returns the channel that closes when the current deadline elapses.
+// Callers must re-fetch Done() after each SetDeadline call — the old
+// channel from before a re-arm is never closed.
+func (d *DeadlineTimer) Done() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.ch
+}
+
+// SetDeadline arms the timer to close Done()'s channel at t, replacing any
+// previous deadline. A zero t clears the deadline (Done() then blocks
+// forever until the next SetDeadline).
+func (d *DeadlineTimer) SetDeadline(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.ch = make(chan struct{})
+
+	if t.IsZero() {
+		d.timer = nil
+		return
+	}
+	ch := d.ch
+	d.timer = time.AfterFunc(time.Until(t), func() { close(ch) })
+}
+
+// Stop cancels any pending deadline. It's safe to call more than once.
+func (d *DeadlineTimer) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+}
@@ -0,0 +1,87 @@
+// Package middleware holds small HTTP wrappers shared across routes.
+//
+// LEARNING (Go):
+//   - A middleware is just a function that takes an http.Handler and
+//     returns a new one that wraps it — no framework needed, the type
+//     system does all the work.
+//   - context.WithTimeout derives a child context that cancels itself
+//     once the deadline passes. Every store call already takes ctx, so
+//     wrapping r.Context() here is enough to bound the whole request,
+//     pgx query included.
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// WithTimeout returns middleware that gives each request a time budget:
+// the handler's r.Context() cancels after d, and if the handler hasn't
+// finished writing a response by then, the client gets a 504 instead of
+// waiting indefinitely for whatever the handler (or a still-blocked store
+// call) eventually does.
+//
+// The handler runs against a buffered ResponseWriter so a response it
+// writes after the deadline has already produced a 504 never corrupts
+// the reply that was already sent.
+func WithTimeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			buf := &bufferedResponse{header: make(http.Header)}
+			done := make(chan struct{})
+			go func() {
+				next.ServeHTTP(buf, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				buf.copyTo(w)
+			case <-ctx.Done():
+				writeTimeout(w)
+			}
+		})
+	}
+}
+
+// writeTimeout writes the 504 response for a request that exceeded its
+// time budget.
+func writeTimeout(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusGatewayTimeout)
+	json.NewEncoder(w).Encode(map[string]string{"error": "request exceeded its time budget"})
+}
+
+// bufferedResponse captures a handler's response so WithTimeout can decide
+// whether it's still safe to forward it to the real ResponseWriter.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+func (b *bufferedResponse) WriteHeader(status int) { b.status = status }
+
+// copyTo replays the buffered response onto w.
+func (b *bufferedResponse) copyTo(w http.ResponseWriter) {
+	dst := w.Header()
+	for k, v := range b.header {
+		dst[k] = v
+	}
+	status := b.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(b.body.Bytes())
+}
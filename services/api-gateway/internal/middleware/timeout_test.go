@@ -0,0 +1,126 @@
+// Package middleware — tests for request timeouts.
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestWithTimeout_FastHandlerPassesThrough(t *testing.T) {
+	h := WithTimeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Body.String() != "ok" {
+		t.Errorf("body = %q, want %q", rec.Body.String(), "ok")
+	}
+}
+
+func TestWithTimeout_SlowHandlerGets504(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	h := WithTimeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate a slow store call that honors ctx cancellation, the
+		// same way pgx would once the request's context is cancelled.
+		select {
+		case <-r.Context().Done():
+		case <-unblock:
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusGatewayTimeout)
+	}
+	var body map[string]string
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body["error"] == "" {
+		t.Error("expected a structured JSON error body")
+	}
+}
+
+func TestWithTimeout_CancelsDownstreamContext(t *testing.T) {
+	sawCancel := make(chan error, 1)
+
+	h := WithTimeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		sawCancel <- r.Context().Err()
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	select {
+	case err := <-sawCancel:
+		if err != context.DeadlineExceeded {
+			t.Errorf("ctx.Err() = %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("downstream context was never cancelled")
+	}
+}
+
+func TestDeadlineTimer_FiresAtDeadline(t *testing.T) {
+	d := NewDeadlineTimer()
+	d.SetDeadline(time.Now().Add(10 * time.Millisecond))
+
+	select {
+	case <-d.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done() never closed at the deadline")
+	}
+}
+
+func TestDeadlineTimer_ReArmInvalidatesOldChannel(t *testing.T) {
+	d := NewDeadlineTimer()
+	d.SetDeadline(time.Now().Add(10 * time.Millisecond))
+	first := d.Done()
+
+	// Re-arming before the first deadline fires replaces it entirely, the
+	// same way a second net.Conn.SetDeadline call invalidates the first.
+	d.SetDeadline(time.Now().Add(time.Hour))
+	second := d.Done()
+
+	select {
+	case <-first:
+		t.Fatal("the old channel fired even though the deadline was re-armed before it elapsed")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	select {
+	case <-second:
+		t.Fatal("the re-armed channel fired early")
+	case <-time.After(30 * time.Millisecond):
+	}
+}
+
+func TestDeadlineTimer_StopLeavesNoGoroutineRunning(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	d := NewDeadlineTimer()
+	for i := 0; i < 50; i++ {
+		d.SetDeadline(time.Now().Add(time.Hour))
+	}
+	d.Stop()
+
+	time.Sleep(10 * time.Millisecond)
+	after := runtime.NumGoroutine()
+	if after > before+2 { // small slack for the test runner's own goroutines
+		t.Errorf("goroutine count = %d, want close to pre-test count %d", after, before)
+	}
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventCursor identifies a position in the events table for keyset
+// pagination. (created_at, id) is unique and monotonic with ORDER BY
+// created_at DESC, id DESC, so it's enough to seek from without OFFSET.
+type EventCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        int64     `json:"id"`
+}
+
+// EncodeCursor serializes a cursor into an opaque string safe to hand back
+// to clients in a URL query parameter.
+func EncodeCursor(c EventCursor) string {
+	b, _ := json.Marshal(c) // EventCursor always marshals cleanly
+	return base64.URLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor. Callers should
+// treat a non-nil error as a client error (bad cursor), not a server one.
+func DecodeCursor(s string) (EventCursor, error) {
+	b, err := base64.URLEncoding.DecodeString(s)
+	if err != nil {
+		return EventCursor{}, fmt.Errorf("store: invalid cursor: %w", err)
+	}
+	var c EventCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return EventCursor{}, fmt.Errorf("store: invalid cursor: %w", err)
+	}
+	return c, nil
+}
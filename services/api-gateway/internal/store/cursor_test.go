@@ -0,0 +1,25 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	want := EventCursor{CreatedAt: time.Date(2026, 7, 27, 12, 0, 0, 0, time.UTC), ID: 42}
+
+	encoded := EncodeCursor(want)
+	got, err := DecodeCursor(encoded)
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Errorf("DecodeCursor() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCursor_RejectsGarbage(t *testing.T) {
+	if _, err := DecodeCursor("not valid base64!!"); err == nil {
+		t.Error("DecodeCursor() error = nil, want an error for invalid input")
+	}
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ListEventsParams filters and paginates ListEventsPage. The zero value
+// lists the most recent events of every type, 50 per page (ListEventsPage
+// applies that default whenever Limit is not positive).
+type ListEventsParams struct {
+	EventType string
+	Limit     int
+	Since     time.Time    // zero means no lower bound
+	Until     time.Time    // zero means no upper bound
+	After     *EventCursor // seek strictly older than this cursor
+	Before    *EventCursor // seek strictly newer than this cursor
+}
+
+// EventPage is a page of events plus cursors for the adjacent pages.
+// NextCursor is empty when there is no older page; PrevCursor is empty
+// when the page already starts at the newest event.
+type EventPage struct {
+	Events     []Event `json:"events"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	PrevCursor string  `json:"prev_cursor,omitempty"`
+}
+
+// ListEventsPage returns one page of events ordered newest-first, using
+// keyset pagination on (created_at, id) instead of OFFSET so paging stays
+// fast regardless of how deep into the table the caller seeks.
+func (s *Store) ListEventsPage(ctx context.Context, p ListEventsParams) (*EventPage, error) {
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query, args, backward := buildEventsPageQuery(p, limit)
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e.ID, &e.EventType, &e.VideoID, &e.ChannelID,
+			&e.Title, &e.ChannelTitle, &e.DurationSecs, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return assembleEventsPage(events, limit, backward, p), nil
+}
+
+// buildEventsPageQuery builds the SQL and args for one page of
+// ListEventsPage. backward reports whether this is a Before (seek-newer)
+// query: those need ASCENDING order so LIMIT takes the rows immediately
+// after the cursor, not the globally newest rows — ORDER BY ... DESC would
+// always return page one regardless of the cursor.
+func buildEventsPageQuery(p ListEventsParams, limit int) (query string, args []any, backward bool) {
+	query = `SELECT id, event_type, video_id, channel_id, title, channel_title, duration_seconds, created_at
+	           FROM events`
+	var conditions []string
+	argN := 1
+
+	addCond := func(cond string, vals ...any) {
+		conditions = append(conditions, cond)
+		args = append(args, vals...)
+		argN += len(vals)
+	}
+
+	if p.EventType != "" {
+		addCond("event_type = $"+itoa(argN), p.EventType)
+	}
+	if !p.Since.IsZero() {
+		addCond("created_at >= $"+itoa(argN), p.Since)
+	}
+	if !p.Until.IsZero() {
+		addCond("created_at <= $"+itoa(argN), p.Until)
+	}
+	if p.After != nil {
+		addCond(fmt.Sprintf("(created_at, id) < ($%d, $%d)", argN, argN+1), p.After.CreatedAt, p.After.ID)
+	}
+	if p.Before != nil {
+		addCond(fmt.Sprintf("(created_at, id) > ($%d, $%d)", argN, argN+1), p.Before.CreatedAt, p.Before.ID)
+	}
+
+	for i, cond := range conditions {
+		if i == 0 {
+			query += " WHERE "
+		} else {
+			query += " AND "
+		}
+		query += cond
+	}
+
+	backward = p.After == nil && p.Before != nil
+	dir := "DESC"
+	if backward {
+		dir = "ASC"
+	}
+	query += " ORDER BY created_at " + dir + ", id " + dir
+
+	// Fetch one extra row so we can tell whether another page exists
+	// without a second COUNT query.
+	args = append(args, limit+1)
+	query += " LIMIT $" + itoa(argN)
+
+	return query, args, backward
+}
+
+// assembleEventsPage turns the rows fetched for one page (in query order —
+// descending unless backward) into an EventPage in the public, always
+// newest-first order, computing NextCursor/PrevCursor from its edges.
+func assembleEventsPage(events []Event, limit int, backward bool, p ListEventsParams) *EventPage {
+	page := &EventPage{}
+	hasMore := len(events) > limit
+	if hasMore {
+		events = events[:limit]
+	}
+	if backward {
+		reverseEvents(events)
+	}
+	page.Events = events
+
+	// The cursor bounds the page on one side (After: newer rows exist,
+	// Before: older rows exist); hasMore tells about the other side, which
+	// for a backward query is the newer one.
+	hasOlder, hasNewer := hasMore, p.After != nil || p.Before != nil
+	if backward {
+		hasOlder, hasNewer = true, hasMore
+	}
+	if hasOlder && len(events) > 0 {
+		last := events[len(events)-1]
+		page.NextCursor = EncodeCursor(EventCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	if hasNewer && len(events) > 0 {
+		first := events[0]
+		page.PrevCursor = EncodeCursor(EventCursor{CreatedAt: first.CreatedAt, ID: first.ID})
+	}
+	return page
+}
+
+// reverseEvents reverses events in place.
+func reverseEvents(events []Event) {
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+}
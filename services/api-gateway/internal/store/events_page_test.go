@@ -0,0 +1,134 @@
+package store
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// applyParams simulates, in Go, what the SQL built by buildEventsPageQuery
+// would return for p's After/Before cursors against an in-memory table (it
+// ignores EventType, Since and Until) —
+// internal/store has no query-recording fake to run against, so this lets
+// the cursor direction/ordering logic be tested without a live Postgres.
+// allDesc must already be sorted newest-first, matching the table's
+// natural (created_at DESC, id DESC) order.
+func applyParams(allDesc []Event, p ListEventsParams, limit int) []Event {
+	var filtered []Event
+	for _, e := range allDesc {
+		if p.After != nil && !beforeCursor(e, *p.After) {
+			continue
+		}
+		if p.Before != nil && !afterCursor(e, *p.Before) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+
+	backward := p.After == nil && p.Before != nil
+	if backward {
+		// ORDER BY ... ASC: closest-to-cursor rows come first, which for a
+		// newest-first filtered slice means reversing it.
+		reverseEvents(filtered)
+	}
+	if len(filtered) > limit+1 {
+		filtered = filtered[:limit+1]
+	}
+	return filtered
+}
+
+func beforeCursor(e Event, c EventCursor) bool {
+	if e.CreatedAt.Before(c.CreatedAt) {
+		return true
+	}
+	return e.CreatedAt.Equal(c.CreatedAt) && e.ID < c.ID
+}
+
+func afterCursor(e Event, c EventCursor) bool {
+	if e.CreatedAt.After(c.CreatedAt) {
+		return true
+	}
+	return e.CreatedAt.Equal(c.CreatedAt) && e.ID > c.ID
+}
+
+func seedDescEvents(n int) []Event {
+	base := time.Date(2026, 7, 27, 0, 0, 0, 0, time.UTC)
+	events := make([]Event, n)
+	for i := 0; i < n; i++ {
+		id := int64(n - i) // events[0] is the newest
+		events[i] = Event{ID: id, EventType: "watch", CreatedAt: base.Add(time.Duration(id) * time.Minute)}
+	}
+	return events
+}
+
+func firstLastID(events []Event) (first, last int64) {
+	return events[0].ID, events[len(events)-1].ID
+}
+
+// TestListEventsPage_WalkForwardThenBack reproduces the maintainer-reported
+// bug: paging forward to page 3 and then following its own PrevCursor must
+// land back on page 2, not jump to page 1.
+func TestListEventsPage_WalkForwardThenBack(t *testing.T) {
+	all := seedDescEvents(100)
+	const limit = 10
+
+	page1 := assembleEventsPage(applyParams(all, ListEventsParams{Limit: limit}, limit), limit, false, ListEventsParams{})
+	if first, last := firstLastID(page1.Events); first != 100 || last != 91 {
+		t.Fatalf("page1 = %d..%d, want 100..91", first, last)
+	}
+
+	after1, err := DecodeCursor(page1.NextCursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor(page1.NextCursor) error = %v", err)
+	}
+	p2 := ListEventsParams{Limit: limit, After: &after1}
+	page2 := assembleEventsPage(applyParams(all, p2, limit), limit, p2.After == nil && p2.Before != nil, p2)
+	if first, last := firstLastID(page2.Events); first != 90 || last != 81 {
+		t.Fatalf("page2 = %d..%d, want 90..81", first, last)
+	}
+
+	after2, err := DecodeCursor(page2.NextCursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor(page2.NextCursor) error = %v", err)
+	}
+	p3 := ListEventsParams{Limit: limit, After: &after2}
+	page3 := assembleEventsPage(applyParams(all, p3, limit), limit, p3.After == nil && p3.Before != nil, p3)
+	if first, last := firstLastID(page3.Events); first != 80 || last != 71 {
+		t.Fatalf("page3 = %d..%d, want 80..71", first, last)
+	}
+
+	// Now walk back using page3's own PrevCursor. Before the fix this
+	// returned the globally newest page (100..91) instead of page 2.
+	before3, err := DecodeCursor(page3.PrevCursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor(page3.PrevCursor) error = %v", err)
+	}
+	pBack := ListEventsParams{Limit: limit, Before: &before3}
+	backward := pBack.After == nil && pBack.Before != nil
+	pageBack := assembleEventsPage(applyParams(all, pBack, limit), limit, backward, pBack)
+	if first, last := firstLastID(pageBack.Events); first != 90 || last != 81 {
+		t.Fatalf("page back from page3.PrevCursor = %d..%d, want 90..81 (page 2)", first, last)
+	}
+}
+
+func TestBuildEventsPageQuery_BeforeUsesAscendingOrder(t *testing.T) {
+	cursor := EventCursor{CreatedAt: time.Now(), ID: 5}
+	query, _, backward := buildEventsPageQuery(ListEventsParams{Before: &cursor}, 10)
+	if !backward {
+		t.Error("backward = false, want true when only Before is set")
+	}
+	if !strings.Contains(query, "ORDER BY created_at ASC, id ASC") {
+		t.Errorf("query = %q, want ascending ORDER BY for a Before-only page", query)
+	}
+}
+
+func TestBuildEventsPageQuery_AfterUsesDescendingOrder(t *testing.T) {
+	cursor := EventCursor{CreatedAt: time.Now(), ID: 5}
+	query, _, backward := buildEventsPageQuery(ListEventsParams{After: &cursor}, 10)
+	if backward {
+		t.Error("backward = true, want false when After is set")
+	}
+	if !strings.Contains(query, "ORDER BY created_at DESC, id DESC") {
+		t.Errorf("query = %q, want descending ORDER BY for an After page", query)
+	}
+}
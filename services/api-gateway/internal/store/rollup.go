@@ -0,0 +1,32 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// RollupStatsHourly writes one pre-aggregated row per event type into
+// stats_hourly for the most recently completed hour, so GetStats can read
+// O(1) rows for its rolling windows instead of COUNT-ing the whole events
+// table. Safe to run more than once for the same hour — it upserts.
+func (s *Store) RollupStatsHourly(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO stats_hourly (bucket_start, event_type, count)
+		SELECT date_trunc('hour', created_at), event_type, COUNT(*)
+		FROM events
+		WHERE created_at >= date_trunc('hour', now()) - interval '1 hour'
+		  AND created_at < date_trunc('hour', now())
+		GROUP BY 1, 2
+		ON CONFLICT (bucket_start, event_type) DO UPDATE SET count = EXCLUDED.count`)
+	return err
+}
+
+// DeleteEventsOlderThan removes events created before the given time and
+// reports how many rows were deleted.
+func (s *Store) DeleteEventsOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, "DELETE FROM events WHERE created_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
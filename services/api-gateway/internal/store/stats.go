@@ -0,0 +1,185 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// statsQuery computes the all-time and today counters in one round-trip
+// using conditional aggregation (COUNT(*) FILTER (WHERE ...)), instead of
+// issuing a separate QueryRow per counter. The rolling windows (24h/7d/30d)
+// are handled separately by rollupWindowQuery/partialHourQuery below, so
+// they can read pre-aggregated stats_hourly rows instead of scanning the
+// whole events table.
+const statsQuery = `
+SELECT
+  COUNT(*) FILTER (WHERE event_type = 'watch'),
+  COUNT(*) FILTER (WHERE event_type = 'like'),
+  COUNT(*) FILTER (WHERE event_type = 'subscribe'),
+  COUNT(*) FILTER (WHERE event_type = 'watch' AND created_at >= CURRENT_DATE),
+  COUNT(*) FILTER (WHERE event_type = 'like' AND created_at >= CURRENT_DATE)
+FROM events`
+
+// rollupWindowQuery sums the pre-aggregated stats_hourly rows written by
+// StatsRollupJob for every completed hour within each rolling window, so
+// GetStats reads a handful of hourly rows instead of COUNT-ing the whole
+// events table. It excludes the current, still-accumulating hour — that
+// sliver is added back in by partialHourQuery.
+const rollupWindowQuery = `
+SELECT
+  COALESCE(SUM(count) FILTER (WHERE event_type = 'watch'     AND bucket_start >= now() - interval '24 hours'), 0),
+  COALESCE(SUM(count) FILTER (WHERE event_type = 'like'      AND bucket_start >= now() - interval '24 hours'), 0),
+  COALESCE(SUM(count) FILTER (WHERE event_type = 'subscribe' AND bucket_start >= now() - interval '24 hours'), 0),
+  COALESCE(SUM(count) FILTER (WHERE event_type = 'watch'     AND bucket_start >= now() - interval '7 days'), 0),
+  COALESCE(SUM(count) FILTER (WHERE event_type = 'like'      AND bucket_start >= now() - interval '7 days'), 0),
+  COALESCE(SUM(count) FILTER (WHERE event_type = 'subscribe' AND bucket_start >= now() - interval '7 days'), 0),
+  COALESCE(SUM(count) FILTER (WHERE event_type = 'watch'     AND bucket_start >= now() - interval '30 days'), 0),
+  COALESCE(SUM(count) FILTER (WHERE event_type = 'like'      AND bucket_start >= now() - interval '30 days'), 0),
+  COALESCE(SUM(count) FILTER (WHERE event_type = 'subscribe' AND bucket_start >= now() - interval '30 days'), 0)
+FROM stats_hourly
+WHERE bucket_start < date_trunc('hour', now())`
+
+// partialHourQuery covers the in-progress hour that RollupStatsHourly
+// hasn't rolled up yet. It's the only live events scan GetStats still
+// does, and it's bounded to at most an hour of rows regardless of window.
+const partialHourQuery = `
+SELECT
+  COUNT(*) FILTER (WHERE event_type = 'watch'),
+  COUNT(*) FILTER (WHERE event_type = 'like'),
+  COUNT(*) FILTER (WHERE event_type = 'subscribe')
+FROM events
+WHERE created_at >= date_trunc('hour', now())`
+
+const downloadsQuery = `SELECT COUNT(*) FROM downloads WHERE status = 'completed'`
+
+// rowScanner is the slice of pgx.Row's API that scanStats needs. It exists
+// so tests can exercise the Scan-error-handling logic with a fake row
+// instead of a real database connection.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanStats reads one statsQuery row into a Stats value. Unlike the old
+// per-counter QueryRow calls, a Scan error here is returned to the caller
+// instead of silently leaving the zero value in place.
+func scanStats(row rowScanner) (*Stats, error) {
+	var st Stats
+	err := row.Scan(&st.TotalWatched, &st.TotalLiked, &st.TotalSubscribed, &st.WatchedToday, &st.LikedToday)
+	if err != nil {
+		return nil, fmt.Errorf("store: scan stats row: %w", err)
+	}
+	return &st, nil
+}
+
+// scanWindowCounts reads a row of three FILTER'd counts (watch, like,
+// subscribe) into a map, the shape shared by rollupWindowQuery's three
+// windows and by partialHourQuery.
+func scanWindowCounts(row rowScanner) (map[string]int, error) {
+	var watch, like, subscribe int
+	if err := row.Scan(&watch, &like, &subscribe); err != nil {
+		return nil, err
+	}
+	return map[string]int{"watch": watch, "like": like, "subscribe": subscribe}, nil
+}
+
+// scanRollupWindows reads one rollupWindowQuery row into its three rolling
+// windows.
+func scanRollupWindows(row rowScanner) (last24h, last7d, last30d map[string]int, err error) {
+	var w24, l24, s24, w7, l7, s7, w30, l30, s30 int
+	err = row.Scan(&w24, &l24, &s24, &w7, &l7, &s7, &w30, &l30, &s30)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	last24h = map[string]int{"watch": w24, "like": l24, "subscribe": s24}
+	last7d = map[string]int{"watch": w7, "like": l7, "subscribe": s7}
+	last30d = map[string]int{"watch": w30, "like": l30, "subscribe": s30}
+	return last24h, last7d, last30d, nil
+}
+
+// addCounts adds src's counts into dst in place.
+func addCounts(dst, src map[string]int) {
+	for k, v := range src {
+		dst[k] += v
+	}
+}
+
+// GetStats returns aggregate counts and rolling windows for the dashboard.
+func (s *Store) GetStats(ctx context.Context) (*Stats, error) {
+	st, err := scanStats(s.pool.QueryRow(ctx, statsQuery))
+	if err != nil {
+		return nil, err
+	}
+
+	last24h, last7d, last30d, err := scanRollupWindows(s.pool.QueryRow(ctx, rollupWindowQuery))
+	if err != nil {
+		return nil, fmt.Errorf("store: scan rollup windows: %w", err)
+	}
+	partial, err := scanWindowCounts(s.pool.QueryRow(ctx, partialHourQuery))
+	if err != nil {
+		return nil, fmt.Errorf("store: scan partial hour: %w", err)
+	}
+	addCounts(last24h, partial)
+	addCounts(last7d, partial)
+	addCounts(last30d, partial)
+	st.Last24h, st.Last7d, st.Last30d = last24h, last7d, last30d
+
+	if err := s.pool.QueryRow(ctx, downloadsQuery).Scan(&st.TotalDownloaded); err != nil {
+		return nil, fmt.Errorf("store: scan download count: %w", err)
+	}
+	return st, nil
+}
+
+// timeSeriesBucketStep maps a valid bucket name to the SQL interval used
+// to step generate_series, so invalid input can be rejected before it ever
+// reaches string interpolation into the query.
+var timeSeriesBucketStep = map[string]string{
+	"hour": "1 hour",
+	"day":  "1 day",
+}
+
+// ValidTimeSeriesBucket reports whether bucket is one GetTimeSeries accepts,
+// so callers like the TimeSeries handler can reject a bad bucket with a 400
+// before it ever reaches the store as a generic error.
+func ValidTimeSeriesBucket(bucket string) bool {
+	_, ok := timeSeriesBucketStep[bucket]
+	return ok
+}
+
+// GetTimeSeries returns one point per bucket between since and now,
+// including empty buckets, optionally filtered to a single event type.
+func (s *Store) GetTimeSeries(ctx context.Context, eventType, bucket string, since time.Time) ([]TimeSeriesPoint, error) {
+	step, ok := timeSeriesBucketStep[bucket]
+	if !ok {
+		return nil, fmt.Errorf("store: invalid bucket %q, want \"hour\" or \"day\"", bucket)
+	}
+
+	// bucket is validated against timeSeriesBucketStep above, so it's safe
+	// to interpolate into date_trunc's first argument (date_trunc doesn't
+	// accept that as a placeholder).
+	query := fmt.Sprintf(`
+		SELECT bucket, COUNT(e.id)
+		FROM generate_series(date_trunc('%s', $1::timestamptz), date_trunc('%s', now()), interval '%s') AS bucket
+		LEFT JOIN events e
+		  ON date_trunc('%s', e.created_at) = bucket
+		 AND e.created_at >= $1
+		 AND ($2 = '' OR e.event_type = $2)
+		GROUP BY bucket
+		ORDER BY bucket`, bucket, bucket, step, bucket)
+
+	rows, err := s.pool.Query(ctx, query, since, eventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []TimeSeriesPoint
+	for rows.Next() {
+		var p TimeSeriesPoint
+		if err := rows.Scan(&p.BucketStart, &p.Count); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
@@ -0,0 +1,116 @@
+// Package store — tests for stats aggregation.
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeRow is a rowScanner whose Scan either fails with a fixed error or
+// copies fixed values into the destinations, letting us exercise scanStats
+// without a real database connection.
+type fakeRow struct {
+	values []int
+	err    error
+}
+
+func (f *fakeRow) Scan(dest ...any) error {
+	if f.err != nil {
+		return f.err
+	}
+	if len(dest) != len(f.values) {
+		return errors.New("fakeRow: dest/values length mismatch")
+	}
+	for i, d := range dest {
+		*(d.(*int)) = f.values[i]
+	}
+	return nil
+}
+
+func TestScanStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		row     *fakeRow
+		wantErr bool
+	}{
+		{
+			name: "all counters scan cleanly",
+			row: &fakeRow{values: []int{
+				100, 50, 20, // total watched/liked/subscribed
+				5, 2, // watched/liked today
+			}},
+		},
+		{
+			name:    "propagates a partial scan failure instead of leaving zero values",
+			row:     &fakeRow{err: errors.New("conversion error")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := scanStats(tt.row)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("scanStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if st.TotalWatched != 100 {
+				t.Errorf("TotalWatched = %d, want 100", st.TotalWatched)
+			}
+			if st.WatchedToday != 5 || st.LikedToday != 2 {
+				t.Errorf("WatchedToday/LikedToday = %d/%d, want 5/2", st.WatchedToday, st.LikedToday)
+			}
+		})
+	}
+}
+
+func TestScanRollupWindows(t *testing.T) {
+	row := &fakeRow{values: []int{
+		10, 4, 1, // 24h: watch/like/subscribe
+		40, 15, 3, // 7d: watch/like/subscribe
+		90, 30, 8, // 30d: watch/like/subscribe
+	}}
+
+	last24h, last7d, last30d, err := scanRollupWindows(row)
+	if err != nil {
+		t.Fatalf("scanRollupWindows() error = %v", err)
+	}
+	if last24h["watch"] != 10 || last24h["like"] != 4 || last24h["subscribe"] != 1 {
+		t.Errorf("last24h = %+v, want watch=10 like=4 subscribe=1", last24h)
+	}
+	if last7d["watch"] != 40 {
+		t.Errorf("last7d[watch] = %d, want 40", last7d["watch"])
+	}
+	if last30d["subscribe"] != 8 {
+		t.Errorf("last30d[subscribe] = %d, want 8", last30d["subscribe"])
+	}
+}
+
+func TestScanRollupWindows_PropagatesScanError(t *testing.T) {
+	row := &fakeRow{err: errors.New("conversion error")}
+	if _, _, _, err := scanRollupWindows(row); err == nil {
+		t.Error("scanRollupWindows() error = nil, want the underlying Scan error")
+	}
+}
+
+func TestAddCounts_MergesThePartialHourIntoAWindow(t *testing.T) {
+	last24h := map[string]int{"watch": 10, "like": 4, "subscribe": 1}
+	partial := map[string]int{"watch": 2, "like": 0, "subscribe": 1}
+
+	addCounts(last24h, partial)
+
+	if last24h["watch"] != 12 || last24h["subscribe"] != 2 {
+		t.Errorf("last24h = %+v, want watch=12 subscribe=2", last24h)
+	}
+}
+
+func TestGetTimeSeries_RejectsUnknownBucket(t *testing.T) {
+	s := &Store{}
+	if _, err := s.GetTimeSeries(context.Background(), "", "week", time.Time{}); err == nil {
+		t.Error("GetTimeSeries() error = nil, want an error for an unsupported bucket")
+	}
+}
@@ -13,14 +13,18 @@ package store
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"yagami/api-gateway/internal/broker"
 )
 
 // Store holds the connection pool and provides query methods.
 type Store struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	broker *broker.Broker
 }
 
 // Event represents a row in the events table.
@@ -35,7 +39,8 @@ type Event struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
-// Stats holds aggregate counts.
+// Stats holds aggregate counts, plus rolling windows per event type so the
+// dashboard can render sparklines without a separate round-trip per window.
 type Stats struct {
 	TotalWatched    int `json:"total_watched"`
 	TotalLiked      int `json:"total_liked"`
@@ -43,6 +48,18 @@ type Stats struct {
 	TotalDownloaded int `json:"total_downloaded"`
 	WatchedToday    int `json:"watched_today"`
 	LikedToday      int `json:"liked_today"`
+
+	// Last24h, Last7d and Last30d map event type ("watch", "like",
+	// "subscribe") to the count of events of that type in the window.
+	Last24h map[string]int `json:"last_24h"`
+	Last7d  map[string]int `json:"last_7d"`
+	Last30d map[string]int `json:"last_30d"`
+}
+
+// TimeSeriesPoint is one bucket of a GetTimeSeries result.
+type TimeSeriesPoint struct {
+	BucketStart time.Time `json:"bucket_start"`
+	Count       int       `json:"count"`
 }
 
 // New creates a Store with a connection pool.
@@ -62,6 +79,25 @@ func New(ctx context.Context, dbURL string) (*Store, error) {
 // Close releases all connections.
 func (s *Store) Close() { s.pool.Close() }
 
+// SetBroker attaches a broker that PublishEvent fans inserted events out
+// to. Streaming works without one (PublishEvent becomes a no-op), so tests
+// and callers that don't care about live updates can skip this.
+func (s *Store) SetBroker(b *broker.Broker) { s.broker = b }
+
+// PublishEvent notifies subscribers (the SSE stream) that a new event was
+// inserted. Ingest writers should call this after a successful INSERT into
+// the events table so GET /api/events/stream can push it out immediately.
+func (s *Store) PublishEvent(e Event) {
+	if s.broker == nil {
+		return
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	s.broker.Publish(e.EventType, payload)
+}
+
 // Ping checks database connectivity.
 func (s *Store) Ping(ctx context.Context) error { return s.pool.Ping(ctx) }
 
@@ -104,21 +140,6 @@ func (s *Store) ListEvents(ctx context.Context, eventType string, limit int) ([]
 	return events, rows.Err()
 }
 
-// GetStats returns aggregate counts for the dashboard.
-func (s *Store) GetStats(ctx context.Context) (*Stats, error) {
-	st := &Stats{}
-	// LEARNING: Each QueryRow().Scan() is a separate DB round-trip.
-	// For a high-traffic app you'd combine these into one query.
-	// For our single-user app this is perfectly fine.
-	s.pool.QueryRow(ctx, "SELECT COUNT(*) FROM events WHERE event_type='watch'").Scan(&st.TotalWatched)
-	s.pool.QueryRow(ctx, "SELECT COUNT(*) FROM events WHERE event_type='like'").Scan(&st.TotalLiked)
-	s.pool.QueryRow(ctx, "SELECT COUNT(*) FROM events WHERE event_type='subscribe'").Scan(&st.TotalSubscribed)
-	s.pool.QueryRow(ctx, "SELECT COUNT(*) FROM downloads WHERE status='completed'").Scan(&st.TotalDownloaded)
-	s.pool.QueryRow(ctx, "SELECT COUNT(*) FROM events WHERE event_type='watch' AND created_at >= CURRENT_DATE").Scan(&st.WatchedToday)
-	s.pool.QueryRow(ctx, "SELECT COUNT(*) FROM events WHERE event_type='like' AND created_at >= CURRENT_DATE").Scan(&st.LikedToday)
-	return st, nil
-}
-
 // itoa converts int to string without importing strconv for one use.
 func itoa(n int) string {
 	if n < 10 {